@@ -1,41 +1,177 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
+	"github.com/MahfujulSagor/go_bank/token"
 )
 
+// defaultShutdownGracePeriod bounds how long Start waits for in-flight
+// requests to finish once a shutdown signal arrives.
+const defaultShutdownGracePeriod = 10 * time.Second
+
 // APIServer serves HTTP requests for the banking service.
 // It uses Gorilla Mux for routing.
 type APIServer struct {
-	Addr  string
-	store Storage
+	Addr                string
+	store               db.Store
+	tokenMaker          token.Maker
+	tokenDuration       time.Duration
+	shutdownGracePeriod time.Duration
 }
 
 // NewAPIServer creates a new APIServer with the given address.
-// The address is in the form ":port".
-func NewAPIServer(addr string, store Storage) *APIServer {
-	return &APIServer{
-		Addr:  addr,
-		store: store,
+// The address is in the form ":port". symmetricKey is used to sign PASETO
+// access tokens and tokenDuration controls how long they stay valid.
+func NewAPIServer(addr string, store db.Store, symmetricKey string, tokenDuration time.Duration) (*APIServer, error) {
+	tokenMaker, err := token.NewPasetoMaker(symmetricKey)
+	if err != nil {
+		return nil, err
 	}
+
+	return &APIServer{
+		Addr:                addr,
+		store:               store,
+		tokenMaker:          tokenMaker,
+		tokenDuration:       tokenDuration,
+		shutdownGracePeriod: defaultShutdownGracePeriod,
+	}, nil
 }
 
-// Start starts the HTTP server and listens for requests.
-// It sets up the routes and handlers.
-func (s *APIServer) Start() {
+// Start runs the HTTP server until it receives SIGINT/SIGTERM, then drains
+// in-flight requests for up to shutdownGracePeriod before returning.
+func (s *APIServer) Start() error {
 	router := mux.NewRouter()
+	router.Use(requestTracingMiddleware)
+
+	router.HandleFunc("/users/signup", makeHTTPHandleFunc(s.handleCreateUser))
+	router.HandleFunc("/users/login", makeHTTPHandleFunc(s.handleLoginUser))
+
+	router.HandleFunc("/account", makeHTTPHandleFunc(authMiddleware(s.tokenMaker, idempotencyMiddleware(s.store, s.handleAccount))))
+	router.HandleFunc("/account/{id}", makeHTTPHandleFunc(authMiddleware(s.tokenMaker, s.handleGetAccountByID)))
+	router.HandleFunc("/account/{id}/transfers", makeHTTPHandleFunc(authMiddleware(s.tokenMaker, s.handleListTransfers)))
+	router.HandleFunc("/transfer", makeHTTPHandleFunc(authMiddleware(s.tokenMaker, idempotencyMiddleware(s.store, s.handleTransfer))))
+
+	httpServer := &http.Server{
+		Addr:              s.Addr,
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go startIdempotencyKeyCleanup(ctx, s.store)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("starting server", "addr", s.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+			return
+		}
+		serverErr <- nil
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, draining in-flight requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownGracePeriod)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+// handleCreateUser handles the POST /users/signup request.
+func (s *APIServer) handleCreateUser(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
+	}
+
+	createUserReq := &CreateUserRequest{}
+	if err := json.NewDecoder(r.Body).Decode(createUserReq); err != nil {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid request payload"})
+	}
+	defer r.Body.Close()
+
+	if createUserReq.Email == "" || createUserReq.Password == "" {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "email and password are required"})
+	}
+
+	hashedPassword, err := hashPassword(createUserReq.Password)
+	if err != nil {
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to hash password"})
+	}
 
-	router.HandleFunc("/account", makeHTTPHandleFunc(s.handleAccount))
-	router.HandleFunc("/account/{id}", makeHTTPHandleFunc(s.handleGetAccountByID))
-	router.HandleFunc("/transfer", makeHTTPHandleFunc(s.handleTransfer))
+	user, err := s.store.CreateUser(r.Context(), db.CreateUserParams{
+		Email:          createUserReq.Email,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			return writeJSON(w, http.StatusForbidden, ApiError{Error: "email already registered"})
+		}
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to create user"})
+	}
 
-	log.Println("Starting server on:", s.Addr)
-	http.ListenAndServe(s.Addr, router)
+	return writeJSON(w, http.StatusCreated, newUserResponse(user))
+}
+
+// handleLoginUser handles the POST /users/login request.
+func (s *APIServer) handleLoginUser(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
+	}
+
+	loginReq := &LoginUserRequest{}
+	if err := json.NewDecoder(r.Body).Decode(loginReq); err != nil {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid request payload"})
+	}
+	defer r.Body.Close()
+
+	user, err := s.store.GetUserByEmail(r.Context(), loginReq.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusNotFound, ApiError{Error: "user not found"})
+		}
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get user"})
+	}
+
+	if err := checkPassword(loginReq.Password, user.HashedPassword); err != nil {
+		return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "incorrect password"})
+	}
+
+	accessToken, _, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.tokenDuration)
+	if err != nil {
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to create access token"})
+	}
+
+	return writeJSON(w, http.StatusOK, LoginUserResponse{
+		AccessToken: accessToken,
+		User:        newUserResponse(user),
+	})
 }
 
 func (s *APIServer) handleAccount(w http.ResponseWriter, r *http.Request) error {
@@ -50,13 +186,18 @@ func (s *APIServer) handleAccount(w http.ResponseWriter, r *http.Request) error
 }
 
 // handleGetAccount handles the GET /account request.
-// It retrieves all accounts from the storage and returns them as JSON.
-// Limited to 10 accounts for simplicity.
+// It retrieves the authenticated user's own accounts and returns them as JSON.
 func (s *APIServer) handleGetAccount(w http.ResponseWriter, r *http.Request) error {
 	if r.Method != http.MethodGet {
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
 	}
-	accounts, err := s.store.GetAccounts()
+
+	payload, ok := authPayloadFromContext(r)
+	if !ok {
+		return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "missing authenticated user"})
+	}
+
+	accounts, err := s.store.ListAccountsByUser(r.Context(), payload.UserID)
 	if err != nil {
 		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get accounts"})
 	}
@@ -69,10 +210,6 @@ func (s *APIServer) handleGetAccount(w http.ResponseWriter, r *http.Request) err
 
 func (s *APIServer) handleGetAccountByID(w http.ResponseWriter, r *http.Request) error {
 	if r.Method == http.MethodGet {
-		if r.Method != http.MethodGet {
-			return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
-		}
-
 		idStr := mux.Vars(r)["id"]
 		if idStr == "" {
 			return writeJSON(w, http.StatusBadRequest, ApiError{Error: "missing account id"})
@@ -83,12 +220,17 @@ func (s *APIServer) handleGetAccountByID(w http.ResponseWriter, r *http.Request)
 			return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid account id"})
 		}
 
-		account, err := s.store.GetAccountByID(id)
+		account, err := s.store.GetAccount(r.Context(), id)
 		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+			}
 			return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get account"})
 		}
-		if account == nil {
-			return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+
+		payload, ok := authPayloadFromContext(r)
+		if !ok || account.UserID != payload.UserID {
+			return writeJSON(w, http.StatusForbidden, ApiError{Error: "account doesn't belong to the authenticated user"})
 		}
 
 		return writeJSON(w, http.StatusOK, account)
@@ -120,13 +262,23 @@ func (s *APIServer) handleCreateAccount(w http.ResponseWriter, r *http.Request)
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "first name and last name are required"})
 	}
 
-	account := NewAccount(createAccountReq.FirstName, createAccountReq.LastName)
-	id, err := s.store.CreateAccount(account)
+	payload, ok := authPayloadFromContext(r)
+	if !ok {
+		return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "missing authenticated user"})
+	}
+
+	account, err := s.store.CreateAccount(r.Context(), db.CreateAccountParams{
+		UserID:    payload.UserID,
+		FirstName: createAccountReq.FirstName,
+		LastName:  createAccountReq.LastName,
+		Number:    randomAccountNumber(),
+		Balance:   0,
+	})
 	if err != nil {
 		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to create accont"})
 	}
 	return writeJSON(w, http.StatusCreated, map[string]int64{
-		"id": id,
+		"id": account.ID,
 	})
 }
 
@@ -145,16 +297,25 @@ func (s *APIServer) handleDeleteAccount(w http.ResponseWriter, r *http.Request)
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid account id"})
 	}
 
-	deletedID, err := s.store.DeleteAccount(id)
+	existingAccount, err := s.store.GetAccount(r.Context(), id)
 	if err != nil {
-		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to delete account"})
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+		}
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get account"})
+	}
+
+	payload, ok := authPayloadFromContext(r)
+	if !ok || existingAccount.UserID != payload.UserID {
+		return writeJSON(w, http.StatusForbidden, ApiError{Error: "account doesn't belong to the authenticated user"})
 	}
-	if deletedID == 0 {
-		return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+
+	if err := s.store.DeleteAccount(r.Context(), id); err != nil {
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to delete account"})
 	}
 
 	return writeJSON(w, http.StatusOK, map[string]int64{
-		"id": deletedID,
+		"id": id,
 	})
 }
 
@@ -186,33 +347,35 @@ func (s *APIServer) handleUpdateAccountBalance(w http.ResponseWriter, r *http.Re
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid account number"})
 	}
 
-	// Check if account exists
-	existingAccount, err := s.store.GetAccountByID(id)
+	existingAccount, err := s.store.GetAccount(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+		}
 		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get account"})
 	}
-	if existingAccount == nil {
-		return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+	if existingAccount.Number != updateAccountReq.Number {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "account number mismatch"})
 	}
 
-	account := &Account{
-		ID:      id,
-		Balance: updateAccountReq.Balance,
-		Number:  updateAccountReq.Number,
+	payload, ok := authPayloadFromContext(r)
+	if !ok || existingAccount.UserID != payload.UserID {
+		return writeJSON(w, http.StatusForbidden, ApiError{Error: "account doesn't belong to the authenticated user"})
 	}
 
-	updatedID, err := s.store.UpdateAccountBalance(id, account)
+	account, err := s.store.UpdateAccountBalance(r.Context(), db.UpdateAccountBalanceParams{
+		ID:      id,
+		Balance: updateAccountReq.Balance,
+	})
 	if err != nil {
 		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to update account"})
 	}
-	if updatedID == 0 {
-		return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
-	}
 
 	return writeJSON(w, http.StatusOK, map[string]int64{
-		"id": updatedID,
+		"id": account.ID,
 	})
 }
+
 func (s *APIServer) handleTransfer(w http.ResponseWriter, r *http.Request) error {
 	if r.Method != http.MethodPost {
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
@@ -232,7 +395,28 @@ func (s *APIServer) handleTransfer(w http.ResponseWriter, r *http.Request) error
 		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "amount must be greater than zero"})
 	}
 
-	if err := s.store.TransferMoney(transferReq.FromAccountNo, transferReq.ToAccountNo, transferReq.Amount); err != nil {
+	fromAccount, err := s.store.GetAccountByNumber(r.Context(), transferReq.FromAccountNo)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusNotFound, ApiError{Error: "from account not found"})
+		}
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get account"})
+	}
+
+	payload, ok := authPayloadFromContext(r)
+	if !ok || fromAccount.UserID != payload.UserID {
+		return writeJSON(w, http.StatusForbidden, ApiError{Error: "from account doesn't belong to the authenticated user"})
+	}
+
+	if fromAccount.Balance < transferReq.Amount {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "insufficient funds"})
+	}
+
+	if _, err := s.store.TransferTx(r.Context(), db.TransferTxParams{
+		FromAccountNo: transferReq.FromAccountNo,
+		ToAccountNo:   transferReq.ToAccountNo,
+		Amount:        transferReq.Amount,
+	}); err != nil {
 		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to transfer funds"})
 	}
 	return writeJSON(w, http.StatusOK, map[string]string{
@@ -240,6 +424,57 @@ func (s *APIServer) handleTransfer(w http.ResponseWriter, r *http.Request) error
 	})
 }
 
+// handleListTransfers handles GET /account/{id}/transfers, paging through the
+// transfer history for an account the caller owns.
+func (s *APIServer) handleListTransfers(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "method not allowed"})
+	}
+
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid account id"})
+	}
+
+	account, err := s.store.GetAccount(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusNotFound, ApiError{Error: "account not found"})
+		}
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to get account"})
+	}
+
+	payload, ok := authPayloadFromContext(r)
+	if !ok || account.UserID != payload.UserID {
+		return writeJSON(w, http.StatusForbidden, ApiError{Error: "account doesn't belong to the authenticated user"})
+	}
+
+	limit := int32(10)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = int32(parsed)
+		}
+	}
+	offset := int32(0)
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = int32(parsed)
+		}
+	}
+
+	transfers, err := s.store.ListTransfers(r.Context(), db.ListTransfersParams{
+		FromAccountNo: account.Number,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to list transfers"})
+	}
+
+	return writeJSON(w, http.StatusOK, transfers)
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -258,6 +493,8 @@ type ApiError struct {
 func makeHTTPHandleFunc(f apiFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := f(w, r); err != nil {
+			requestID, _ := requestIDFromContext(r.Context())
+			logger.Error("handler error", "request_id", requestID, "path", r.URL.Path, "error", err)
 			writeJSON(w, http.StatusInternalServerError, ApiError{Error: err.Error()})
 		}
 	}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/MahfujulSagor/go_bank/db/mock"
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
+	"github.com/MahfujulSagor/go_bank/token"
+)
+
+func newTestServer(t *testing.T, store db.Store) *APIServer {
+	server, err := NewAPIServer(":0", store, "12345678901234567890123456789012", time.Minute)
+	require.NoError(t, err)
+	return server
+}
+
+func addAuthHeader(t *testing.T, request *http.Request, tokenMaker token.Maker, userID int64) {
+	accessToken, _, err := tokenMaker.CreateToken(userID, "user@example.com", time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authHeaderKey, fmt.Sprintf("%s %s", authTypeBearer, accessToken))
+}
+
+func TestGetAccountByIDAPI(t *testing.T) {
+	account := db.Account{
+		ID:     1,
+		UserID: 42,
+		Number: 123456,
+	}
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		buildStubs    func(store *mockdb.MockStore)
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthHeader(t, request, tokenMaker, account.UserID)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthHeader(t, request, tokenMaker, account.UserID)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthHeader(t, request, tokenMaker, account.UserID+1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/account/{id}", makeHTTPHandleFunc(authMiddleware(server.tokenMaker, server.handleGetAccountByID)))
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/account/%d", tc.accountID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateAccountAPI(t *testing.T) {
+	userID := int64(7)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateAccount(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.Account{ID: 1, UserID: userID, FirstName: "Jane", LastName: "Doe"}, nil)
+
+	server := newTestServer(t, store)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/account", makeHTTPHandleFunc(authMiddleware(server.tokenMaker, server.handleAccount)))
+
+	body, err := json.Marshal(CreateAccountRequest{FirstName: "Jane", LastName: "Doe"})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/account", bytes.NewReader(body))
+	require.NoError(t, err)
+	addAuthHeader(t, request, server.tokenMaker, userID)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestGetAccountAPI(t *testing.T) {
+	userID := int64(7)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountsByUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return([]db.Account{{ID: 1, UserID: userID}, {ID: 2, UserID: userID}}, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthHeader(t, request, tokenMaker, userID)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var accounts []db.Account
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &accounts))
+				for _, account := range accounts {
+					require.Equal(t, userID, account.UserID)
+				}
+			},
+		},
+		{
+			name: "NoAccounts",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountsByUser(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return([]db.Account{}, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthHeader(t, request, tokenMaker, userID)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountsByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/account", makeHTTPHandleFunc(authMiddleware(server.tokenMaker, server.handleAccount)))
+
+			request, err := http.NewRequest(http.MethodGet, "/account", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package config loads runtime configuration for the banking service from
+// an app.env file and the environment, so it can run in containers/CI
+// without recompiling.
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds all configuration required by the service. Values are read
+// from an env file and/or environment variables, using the "mapstructure"
+// tag as the lookup key.
+type Config struct {
+	DBDriver            string        `mapstructure:"DB_DRIVER"`
+	DBSource            string        `mapstructure:"DB_SOURCE"`
+	ServerAddr          string        `mapstructure:"SERVER_ADDR"`
+	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+}
+
+// Load reads configuration from an env file named "app.env" in path,
+// overlaying any matching environment variables, and validates that every
+// required key is present.
+func Load(path string) (Config, error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+
+	viper.AutomaticEnv()
+
+	var config Config
+	if err := viper.ReadInConfig(); err != nil {
+		return config, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		return config, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.DBDriver == "":
+		return fmt.Errorf("missing required config: DB_DRIVER")
+	case c.DBSource == "":
+		return fmt.Errorf("missing required config: DB_SOURCE")
+	case c.ServerAddr == "":
+		return fmt.Errorf("missing required config: SERVER_ADDR")
+	case c.TokenSymmetricKey == "":
+		return fmt.Errorf("missing required config: TOKEN_SYMMETRIC_KEY")
+	case c.AccessTokenDuration == 0:
+		return fmt.Errorf("missing required config: ACCESS_TOKEN_DURATION")
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Store composes all of the generated Queries with TransferTx, the one
+// operation that must run several queries inside a single transaction.
+type Store interface {
+	Querier
+	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+}
+
+// Querier is implemented by *Queries; it exists so Store (and its mock) can
+// embed the full set of generated query methods.
+type Querier interface {
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, number int64) (Account, error)
+	GetAccountForUpdate(ctx context.Context, number int64) (Account, error)
+	ListAccountsByUser(ctx context.Context, userID int64) ([]Account, error)
+	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	DeleteAccount(ctx context.Context, id int64) error
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, key string) (IdempotencyKey, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, createdBefore time.Time) error
+}
+
+// SQLStore is the production Store backed by a *sql.DB.
+type SQLStore struct {
+	db *sql.DB
+	*Queries
+}
+
+func NewStore(db *sql.DB) Store {
+	return &SQLStore{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx runs fn inside a transaction, rolling back on error and committing
+// otherwise. Higher-level operations that must touch more than one table
+// (TransferTx) compose their generated queries through the *Queries handed
+// to fn instead of the store's own.
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	if err := fn(q); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx error: %v, rollback error: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferTxParams contains the input parameters of the transfer transaction.
+type TransferTxParams struct {
+	FromAccountNo int64   `json:"from_account_no"`
+	ToAccountNo   int64   `json:"to_account_no"`
+	Amount        float64 `json:"amount"`
+}
+
+// TransferTxResult is the result of the transfer transaction.
+type TransferTxResult struct {
+	Transfer    Transfer `json:"transfer"`
+	FromAccount Account  `json:"from_account"`
+	ToAccount   Account  `json:"to_account"`
+	FromEntry   Entry    `json:"from_entry"`
+	ToEntry     Entry    `json:"to_entry"`
+}
+
+// TransferTx moves money between two accounts in a single transaction. It
+// creates a transfer record, two entries, and updates both account
+// balances. Both account rows are locked in ascending account-number order
+// so two concurrent transfers going in opposite directions can't deadlock
+// each other.
+func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock both account rows, low-number first, before anything else in
+		// the transaction touches them. A transfer's CreateTransfer/CreateEntry
+		// inserts take an implicit FOR KEY SHARE lock on the referenced account
+		// rows in from->to order; taking the explicit FOR UPDATE locks after
+		// those inserts would let two opposite-direction transfers each hold a
+		// FOR KEY SHARE lock the other needs to upgrade, deadlocking even
+		// though the explicit locks themselves are acquired in sorted order.
+		lowNumber, highNumber := arg.FromAccountNo, arg.ToAccountNo
+		if highNumber < lowNumber {
+			lowNumber, highNumber = highNumber, lowNumber
+		}
+		lowAccount, err := q.GetAccountForUpdate(ctx, lowNumber)
+		if err != nil {
+			return err
+		}
+		highAccount, err := q.GetAccountForUpdate(ctx, highNumber)
+		if err != nil {
+			return err
+		}
+
+		fromAccount := lowAccount
+		if arg.FromAccountNo != lowNumber {
+			fromAccount = highAccount
+		}
+		if fromAccount.Balance < arg.Amount {
+			return fmt.Errorf("insufficient funds")
+		}
+
+		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountNo: arg.FromAccountNo,
+			ToAccountNo:   arg.ToAccountNo,
+			Amount:        arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountNumber: arg.FromAccountNo,
+			Amount:        -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountNumber: arg.ToAccountNo,
+			Amount:        arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.FromAccountNo < arg.ToAccountNo {
+			result.FromAccount, result.ToAccount, err = addBalances(ctx, q, arg.FromAccountNo, -arg.Amount, arg.ToAccountNo, arg.Amount)
+		} else {
+			result.ToAccount, result.FromAccount, err = addBalances(ctx, q, arg.ToAccountNo, arg.Amount, arg.FromAccountNo, -arg.Amount)
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// addBalances locks and updates two accounts by number in the caller-chosen
+// order, so concurrent transfers always acquire locks low-number-first.
+func addBalances(
+	ctx context.Context,
+	q *Queries,
+	number1 int64,
+	amount1 float64,
+	number2 int64,
+	amount2 float64,
+) (account1, account2 Account, err error) {
+	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		Number: number1,
+		Amount: amount1,
+	})
+	if err != nil {
+		return
+	}
+
+	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		Number: number2,
+		Amount: amount2,
+	})
+	return
+}
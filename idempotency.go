@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyTTL is how long a stored idempotency key and its response
+// are kept around before the cleanup goroutine deletes them.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotencyMiddleware wraps a POST apiFunc so that retried requests
+// carrying the same "Idempotency-Key" header replay the stored response
+// instead of re-executing it. A key reused with a different request body is
+// rejected with 409 Conflict, and a key whose original request is still in
+// flight (including two concurrent requests racing to create the same key)
+// is also rejected with 409 rather than replayed. Requests without the
+// header pass through unchanged.
+func idempotencyMiddleware(store db.Store, next apiFunc) apiFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			return next(w, r)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return writeJSON(w, http.StatusBadRequest, ApiError{Error: "invalid request payload"})
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(hash[:])
+
+		existing, err := store.GetIdempotencyKey(r.Context(), key)
+		if err == nil {
+			return replayIdempotencyKey(w, existing, requestHash)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to check idempotency key"})
+		}
+
+		var userID int64
+		if payload, ok := authPayloadFromContext(r); ok {
+			userID = payload.UserID
+		}
+
+		if _, err := store.CreateIdempotencyKey(r.Context(), db.CreateIdempotencyKeyParams{
+			Key:         key,
+			UserID:      userID,
+			RequestHash: requestHash,
+		}); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+				// Another request for the same key won the race and inserted
+				// its row first; replay against it instead of failing.
+				existing, err := store.GetIdempotencyKey(r.Context(), key)
+				if err != nil {
+					return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to check idempotency key"})
+				}
+				return replayIdempotencyKey(w, existing, requestHash)
+			}
+			return writeJSON(w, http.StatusInternalServerError, ApiError{Error: "failed to record idempotency key"})
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		handlerErr := next(rec, r)
+
+		// The handler has already written a response (or failed trying to),
+		// so this failure can only be logged, not turned into a second
+		// response write.
+		if _, err := store.UpdateIdempotencyKeyResponse(r.Context(), db.UpdateIdempotencyKeyResponseParams{
+			Key:            key,
+			ResponseStatus: int32(rec.status),
+			ResponseBody:   rec.body.Bytes(),
+		}); err != nil {
+			requestID, _ := requestIDFromContext(r.Context())
+			logger.Error("failed to persist idempotency key response", "request_id", requestID, "key", key, "error", err)
+		}
+		return handlerErr
+	}
+}
+
+// replayIdempotencyKey writes the response stored against a reused
+// idempotency key, rejecting reuse with a different body and refusing to
+// replay a key whose original request hasn't finished (response_status is
+// still the migration's unset default of 0, which net/http would panic on
+// if passed to WriteHeader).
+func replayIdempotencyKey(w http.ResponseWriter, existing db.IdempotencyKey, requestHash string) error {
+	if existing.RequestHash != requestHash {
+		return writeJSON(w, http.StatusConflict, ApiError{Error: "idempotency key was already used with a different request"})
+	}
+	if existing.ResponseStatus == 0 {
+		return writeJSON(w, http.StatusConflict, ApiError{Error: "a request with this idempotency key is still in progress"})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(existing.ResponseStatus))
+	_, err := w.Write(existing.ResponseBody)
+	return err
+}
+
+// startIdempotencyKeyCleanup periodically deletes idempotency keys older
+// than idempotencyKeyTTL until ctx is done.
+func startIdempotencyKeyCleanup(ctx context.Context, store db.Store) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			store.DeleteExpiredIdempotencyKeys(ctx, time.Now().UTC().Add(-idempotencyKeyTTL))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/MahfujulSagor/go_bank/db/mock"
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
+)
+
+func hashOf(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	reqBody, err := json.Marshal(map[string]string{"first_name": "Jane", "last_name": "Doe"})
+	require.NoError(t, err)
+	requestHash := hashOf(reqBody)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FreshKey",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("fresh-key")).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "ReplaySameBody",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("replay-key")).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Key:            "replay-key",
+						RequestHash:    requestHash,
+						ResponseStatus: http.StatusCreated,
+						ResponseBody:   []byte(`{"id":1}`),
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, `{"id":1}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "SameKeyDifferentBody",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("conflict-key")).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Key:            "conflict-key",
+						RequestHash:    "some-other-hash",
+						ResponseStatus: http.StatusCreated,
+						ResponseBody:   []byte(`{"id":1}`),
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "StillInProgress",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("in-flight-key")).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Key:         "in-flight-key",
+						RequestHash: requestHash,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ConcurrentCreateFallsBackToReplay",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("racing-key")).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq("racing-key")).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Key:            "racing-key",
+						RequestHash:    requestHash,
+						ResponseStatus: http.StatusCreated,
+						ResponseBody:   []byte(`{"id":1}`),
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, `{"id":1}`, recorder.Body.String())
+			},
+		},
+	}
+
+	keyByCase := map[string]string{
+		"FreshKey":                          "fresh-key",
+		"ReplaySameBody":                    "replay-key",
+		"SameKeyDifferentBody":              "conflict-key",
+		"StillInProgress":                   "in-flight-key",
+		"ConcurrentCreateFallsBackToReplay": "racing-key",
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			next := func(w http.ResponseWriter, r *http.Request) error {
+				return writeJSON(w, http.StatusCreated, map[string]int64{"id": 1})
+			}
+			handler := makeHTTPHandleFunc(idempotencyMiddleware(store, next))
+
+			request, err := http.NewRequest(http.MethodPost, "/account", bytes.NewReader(reqBody))
+			require.NoError(t, err)
+			request.Header.Set(idempotencyKeyHeader, keyByCase[tc.name])
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const requestIDContextKey contextKey = "request_id"
+const requestIDHeader = "X-Request-ID"
+
+// responseRecorder captures the status and body a handler writes so it can
+// be logged or persisted (e.g. for idempotent replay) after the fact, while
+// still forwarding every write to the real client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// requestTracingMiddleware assigns each request a UUID, injects it into the
+// request context and the X-Request-ID response header, and logs
+// method/path/status/duration/request_id once the handler returns.
+func requestTracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := uuid.NewString()
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := context.WithValue(r.Context(), requestIDContextKey, requestID)
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		logger.Info("request handled",
+			"request_id", requestID,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start).String(),
+		)
+	})
+}
+
+// requestIDFromContext returns the UUID requestTracingMiddleware assigned to
+// the in-flight request.
+func requestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDContextKey).(string)
+	return requestID, ok
+}
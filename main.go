@@ -1,19 +1,46 @@
 package main
 
 import (
-	"log"
+	"database/sql"
+	"log/slog"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/MahfujulSagor/go_bank/config"
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
 )
 
+// logger is the package-wide structured logger, emitting JSON to stdout.
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 func main() {
-	store, err := NewPostgresStorage()
+	cfg, err := config.Load(".")
+	if err != nil {
+		logger.Error("cannot load config", "error", err)
+		os.Exit(1)
+	}
+
+	conn, err := sql.Open(cfg.DBDriver, cfg.DBSource)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logger.Error("cannot connect to db", "error", err)
+		os.Exit(1)
+	}
+	if err := conn.Ping(); err != nil {
+		logger.Error("cannot ping db", "error", err)
+		os.Exit(1)
 	}
 
-	if err := store.Init(); err != nil {
-		log.Fatal("Failed to initialize database:", err)
+	store := db.NewStore(conn)
+
+	server, err := NewAPIServer(cfg.ServerAddr, store, cfg.TokenSymmetricKey, cfg.AccessTokenDuration)
+	if err != nil {
+		logger.Error("failed to create API server", "error", err)
+		os.Exit(1)
 	}
 
-	server := NewAPIServer(":8080", store)
-	server.Start()
+	if err := server.Start(); err != nil {
+		logger.Error("server stopped with error", "error", err)
+		os.Exit(1)
+	}
 }
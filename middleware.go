@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/MahfujulSagor/go_bank/token"
+)
+
+type contextKey string
+
+const authPayloadKey contextKey = "authorization_payload"
+
+const (
+	authHeaderKey  = "Authorization"
+	authTypeBearer = "bearer"
+)
+
+// authMiddleware wraps an apiFunc, requiring a valid "Authorization: Bearer <token>"
+// header and injecting the resulting token.Payload into the request context.
+func authMiddleware(tokenMaker token.Maker, next apiFunc) apiFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		authHeader := r.Header.Get(authHeaderKey)
+		if authHeader == "" {
+			return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "authorization header is not provided"})
+		}
+
+		fields := strings.Fields(authHeader)
+		if len(fields) < 2 {
+			return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "invalid authorization header format"})
+		}
+
+		if strings.ToLower(fields[0]) != authTypeBearer {
+			return writeJSON(w, http.StatusUnauthorized, ApiError{Error: "unsupported authorization type"})
+		}
+
+		payload, err := tokenMaker.VerifyToken(fields[1])
+		if err != nil {
+			return writeJSON(w, http.StatusUnauthorized, ApiError{Error: err.Error()})
+		}
+
+		ctx := context.WithValue(r.Context(), authPayloadKey, payload)
+		return next(w, r.WithContext(ctx))
+	}
+}
+
+// authPayloadFromContext returns the token.Payload injected by authMiddleware.
+func authPayloadFromContext(r *http.Request) (*token.Payload, bool) {
+	payload, ok := r.Context().Value(authPayloadKey).(*token.Payload)
+	return payload, ok
+}
@@ -0,0 +1,48 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Different types of errors returned by the VerifyToken function.
+var (
+	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken = errors.New("token is invalid")
+)
+
+// Payload contains the payload data of the token.
+type Payload struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// NewPayload creates a new token payload with a specific user id and duration.
+func NewPayload(userID int64, email string, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		UserID:    userID,
+		Email:     email,
+		IssuedAt:  time.Now().UTC(),
+		ExpiredAt: time.Now().UTC().Add(duration),
+	}
+	return payload, nil
+}
+
+// Valid checks if the token payload is valid or not.
+func (payload *Payload) Valid() error {
+	if time.Now().UTC().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
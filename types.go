@@ -3,6 +3,8 @@ package main
 import (
 	"math/rand"
 	"time"
+
+	db "github.com/MahfujulSagor/go_bank/db/sqlc"
 )
 
 type CreateAccountRequest struct {
@@ -21,23 +23,39 @@ type TransferRequest struct {
 	Amount        float64 `json:"amount"`
 }
 
-type Account struct {
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UserResponse mirrors db.User but omits the hashed password so it is safe
+// to return to clients.
+type UserResponse struct {
 	ID        int64     `json:"id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Number    int64     `json:"number"`
-	Balance   float64   `json:"balance"`
+	Email     string    `json:"email"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
 
-func NewAccount(firstName, lastName string) *Account {
-	return &Account{
-		FirstName: firstName,
-		LastName:  lastName,
-		Number:    rand.Int63n(1000000000),
-		Balance:   0.0,
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+func newUserResponse(user db.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
 	}
 }
+
+type LoginUserResponse struct {
+	AccessToken string       `json:"access_token"`
+	User        UserResponse `json:"user"`
+}
+
+// randomAccountNumber returns a pseudo-random public account number for a
+// newly created account.
+func randomAccountNumber() int64 {
+	return rand.Int63n(1000000000)
+}